@@ -0,0 +1,238 @@
+package input
+
+import (
+	"image"
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+const (
+	// ErrGetDCFailed happens when the winapi function GetDC fails.
+	ErrGetDCFailed errString = "w32.GetDC failed"
+
+	// ErrCreateCompatibleDCFailed happens when the winapi function
+	// CreateCompatibleDC fails.
+	ErrCreateCompatibleDCFailed errString = "w32.CreateCompatibleDC failed"
+
+	// ErrCreateCompatibleBitmapFailed happens when the winapi function
+	// CreateCompatibleBitmap fails.
+	ErrCreateCompatibleBitmapFailed errString = "w32.CreateCompatibleBitmap failed"
+
+	// ErrBitBltFailed happens when the winapi function BitBlt fails.
+	ErrBitBltFailed errString = "w32.BitBlt failed"
+
+	// ErrGetDIBitsFailed happens when the winapi function GetDIBits fails.
+	ErrGetDIBitsFailed errString = "w32.GetDIBits failed"
+
+	// ErrPrintWindowFailed happens when the winapi function PrintWindow fails.
+	ErrPrintWindowFailed errString = "w32.PrintWindow failed"
+
+	// ErrGetWindowRectFailed happens when the winapi function GetWindowRect
+	// fails.
+	ErrGetWindowRectFailed errString = "w32.GetWindowRect failed"
+
+	// ErrInvalidCaptureSize happens when a capture function is asked to
+	// capture a rectangle that is empty or has a negative width or height.
+	ErrInvalidCaptureSize errString = "capture width and height must be greater than 0"
+)
+
+// printWindowRenderFullContent is PW_RENDERFULLCONTENT, which is not (yet)
+// exposed by the w32 package. It tells PrintWindow to render the window even
+// if it is occluded or minimized, by asking the DWM for its composed image
+// instead of drawing straight to the given DC.
+const printWindowRenderFullContent = 2
+
+// Monitor describes one physical display attached to the system, in virtual
+// screen coordinates (the primary monitor's top-left corner is at 0,0, other
+// monitors may have negative coordinates).
+type Monitor struct {
+	Handle                w32.HMONITOR
+	X, Y, Width, Height   int
+	WorkX, WorkY          int
+	WorkWidth, WorkHeight int
+	Primary               bool
+}
+
+// Monitors returns all monitors currently attached to the system, in the
+// order reported by EnumDisplayMonitors.
+func Monitors() []Monitor {
+	var monitors []Monitor
+	cb := syscall.NewCallback(func(h w32.HMONITOR, hdc w32.HDC, r *w32.RECT, lParam uintptr) uintptr {
+		var info w32.MONITORINFO
+		info.CbSize = uint32(unsafe.Sizeof(info))
+		if !w32.GetMonitorInfo(h, &info) {
+			return 1 // continue enumeration
+		}
+		monitors = append(monitors, Monitor{
+			Handle:     h,
+			X:          int(info.RcMonitor.Left),
+			Y:          int(info.RcMonitor.Top),
+			Width:      int(info.RcMonitor.Right - info.RcMonitor.Left),
+			Height:     int(info.RcMonitor.Bottom - info.RcMonitor.Top),
+			WorkX:      int(info.RcWork.Left),
+			WorkY:      int(info.RcWork.Top),
+			WorkWidth:  int(info.RcWork.Right - info.RcWork.Left),
+			WorkHeight: int(info.RcWork.Bottom - info.RcWork.Top),
+			Primary:    info.DwFlags&w32.MONITORINFOF_PRIMARY != 0,
+		})
+		return 1 // continue enumeration
+	})
+	w32.EnumDisplayMonitors(0, nil, cb, 0)
+	return monitors
+}
+
+// PrimaryMonitor returns the monitor that contains the taskbar and shows the
+// desktop by default. The second return value is false if no monitor reports
+// itself as primary, which should not normally happen.
+func PrimaryMonitor() (Monitor, bool) {
+	for _, m := range Monitors() {
+		if m.Primary {
+			return m, true
+		}
+	}
+	return Monitor{}, false
+}
+
+// CaptureScreen takes a screenshot of the given rectangle in virtual screen
+// coordinates and returns it as an image.Image.
+func CaptureScreen(x, y, w, h int) (image.Image, error) {
+	screenDC := w32.GetDC(0)
+	if screenDC == 0 {
+		return nil, ErrGetDCFailed
+	}
+	defer w32.ReleaseDC(0, screenDC)
+
+	return captureDC(screenDC, x, y, w, h)
+}
+
+// CaptureMonitor takes a screenshot of the given monitor and returns it as an
+// image.Image.
+func CaptureMonitor(m Monitor) (image.Image, error) {
+	return CaptureScreen(m.X, m.Y, m.Width, m.Height)
+}
+
+// CaptureWindow takes a screenshot of the screen area covered by the given
+// window. Parts of the window that are covered by other windows, or the whole
+// window if it is minimized, will not be captured correctly; use
+// CaptureWindowContent for that.
+func CaptureWindow(hwnd w32.HWND) (image.Image, error) {
+	r := w32.GetWindowRect(hwnd)
+	if r == nil {
+		return nil, ErrGetWindowRectFailed
+	}
+	return CaptureScreen(
+		int(r.Left),
+		int(r.Top),
+		int(r.Right-r.Left),
+		int(r.Bottom-r.Top),
+	)
+}
+
+// CaptureWindowContent renders the given window's content straight into a
+// bitmap using PrintWindow, so it works even if the window is occluded by
+// other windows or minimized.
+func CaptureWindowContent(hwnd w32.HWND) (image.Image, error) {
+	r := w32.GetWindowRect(hwnd)
+	if r == nil {
+		return nil, ErrGetWindowRectFailed
+	}
+	w := int(r.Right - r.Left)
+	h := int(r.Bottom - r.Top)
+	if w <= 0 || h <= 0 {
+		return nil, ErrInvalidCaptureSize
+	}
+
+	windowDC := w32.GetDC(hwnd)
+	if windowDC == 0 {
+		return nil, ErrGetDCFailed
+	}
+	defer w32.ReleaseDC(hwnd, windowDC)
+
+	memDC := w32.CreateCompatibleDC(windowDC)
+	if memDC == 0 {
+		return nil, ErrCreateCompatibleDCFailed
+	}
+	defer w32.DeleteDC(memDC)
+
+	bitmap := w32.CreateCompatibleBitmap(windowDC, w, h)
+	if bitmap == 0 {
+		return nil, ErrCreateCompatibleBitmapFailed
+	}
+	defer w32.DeleteObject(w32.HGDIOBJ(bitmap))
+
+	old := w32.SelectObject(memDC, w32.HGDIOBJ(bitmap))
+	defer w32.SelectObject(memDC, old)
+
+	if !w32.PrintWindow(hwnd, memDC, printWindowRenderFullContent) {
+		return nil, ErrPrintWindowFailed
+	}
+
+	return bitmapToImage(memDC, bitmap, w, h)
+}
+
+// captureDC copies the given rectangle from srcDC into a new compatible
+// bitmap and converts it to an image.Image.
+func captureDC(srcDC w32.HDC, x, y, w, h int) (image.Image, error) {
+	if w <= 0 || h <= 0 {
+		return nil, ErrInvalidCaptureSize
+	}
+
+	memDC := w32.CreateCompatibleDC(srcDC)
+	if memDC == 0 {
+		return nil, ErrCreateCompatibleDCFailed
+	}
+	defer w32.DeleteDC(memDC)
+
+	bitmap := w32.CreateCompatibleBitmap(srcDC, w, h)
+	if bitmap == 0 {
+		return nil, ErrCreateCompatibleBitmapFailed
+	}
+	defer w32.DeleteObject(w32.HGDIOBJ(bitmap))
+
+	old := w32.SelectObject(memDC, w32.HGDIOBJ(bitmap))
+	defer w32.SelectObject(memDC, old)
+
+	if !w32.BitBlt(memDC, 0, 0, w, h, srcDC, x, y, w32.SRCCOPY) {
+		return nil, ErrBitBltFailed
+	}
+
+	return bitmapToImage(memDC, bitmap, w, h)
+}
+
+// bitmapToImage reads the pixels of bitmap (currently selected into dc) as
+// top-down 32-bit BGRA and converts them into an *image.RGBA.
+func bitmapToImage(dc w32.HDC, bitmap w32.HBITMAP, w, h int) (image.Image, error) {
+	info := w32.BITMAPINFO{
+		BmiHeader: w32.BITMAPINFOHEADER{
+			BiSize:        uint32(unsafe.Sizeof(w32.BITMAPINFOHEADER{})),
+			BiWidth:       int32(w),
+			BiHeight:      -int32(h), // negative: top-down, origin at top left
+			BiPlanes:      1,
+			BiBitCount:    32,
+			BiCompression: w32.BI_RGB,
+		},
+	}
+
+	pixels := make([]byte, w*h*4)
+	if w32.GetDIBits(dc, bitmap, 0, uint(h), unsafe.Pointer(&pixels[0]), &info, w32.DIB_RGB_COLORS) == 0 {
+		return nil, ErrGetDIBitsFailed
+	}
+
+	// GDI does not fill in a meaningful alpha channel for an ordinary,
+	// non-layered bitmap like the ones BitBlt/PrintWindow produce here (it is
+	// commonly all-zero), so the capture would look fully transparent if we
+	// trusted it. Screenshots are opaque, so force alpha to 0xff instead.
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < w*h; i++ {
+		b := pixels[i*4+0]
+		g := pixels[i*4+1]
+		r := pixels[i*4+2]
+		img.Pix[i*4+0] = r
+		img.Pix[i*4+1] = g
+		img.Pix[i*4+2] = b
+		img.Pix[i*4+3] = 0xff
+	}
+	return img, nil
+}
@@ -0,0 +1,194 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gonutz/w32/v2"
+)
+
+const (
+	// ErrEmptyChord happens when ParseChord is given an empty string or a
+	// chord made up only of separators, e.g. "+".
+	ErrEmptyChord errString = "chord is empty"
+)
+
+// errUnknownKey is returned by ParseChord when one of the names in the chord
+// is not found in KeyByName.
+type errUnknownKey string
+
+func (e errUnknownKey) Error() string {
+	return fmt.Sprintf("unknown key name %q", string(e))
+}
+
+// KeyByName maps human-readable key names, as used by ParseChord, PressChord
+// and TypeKeys, to virtual key codes. Names are case-insensitive; ParseChord
+// lower-cases them before looking them up here.
+var KeyByName = map[string]uint16{
+	"backspace":   w32.VK_BACK,
+	"back":        w32.VK_BACK,
+	"tab":         w32.VK_TAB,
+	"enter":       w32.VK_RETURN,
+	"return":      w32.VK_RETURN,
+	"shift":       w32.VK_SHIFT,
+	"ctrl":        w32.VK_CONTROL,
+	"control":     w32.VK_CONTROL,
+	"alt":         w32.VK_MENU,
+	"pause":       w32.VK_PAUSE,
+	"capslock":    w32.VK_CAPITAL,
+	"esc":         w32.VK_ESCAPE,
+	"escape":      w32.VK_ESCAPE,
+	"space":       w32.VK_SPACE,
+	"pageup":      w32.VK_PRIOR,
+	"pagedown":    w32.VK_NEXT,
+	"end":         w32.VK_END,
+	"home":        w32.VK_HOME,
+	"left":        w32.VK_LEFT,
+	"up":          w32.VK_UP,
+	"right":       w32.VK_RIGHT,
+	"down":        w32.VK_DOWN,
+	"printscreen": w32.VK_SNAPSHOT,
+	"insert":      w32.VK_INSERT,
+	"delete":      w32.VK_DELETE,
+	"del":         w32.VK_DELETE,
+	"win":         w32.VK_LWIN,
+	"lwin":        w32.VK_LWIN,
+	"rwin":        w32.VK_RWIN,
+	"numlock":     w32.VK_NUMLOCK,
+	"scrolllock":  w32.VK_SCROLL,
+
+	"kp_0":        w32.VK_NUMPAD0,
+	"kp_1":        w32.VK_NUMPAD1,
+	"kp_2":        w32.VK_NUMPAD2,
+	"kp_3":        w32.VK_NUMPAD3,
+	"kp_4":        w32.VK_NUMPAD4,
+	"kp_5":        w32.VK_NUMPAD5,
+	"kp_6":        w32.VK_NUMPAD6,
+	"kp_7":        w32.VK_NUMPAD7,
+	"kp_8":        w32.VK_NUMPAD8,
+	"kp_9":        w32.VK_NUMPAD9,
+	"kp_add":      w32.VK_ADD,
+	"kp_subtract": w32.VK_SUBTRACT,
+	"kp_multiply": w32.VK_MULTIPLY,
+	"kp_divide":   w32.VK_DIVIDE,
+	"kp_decimal":  w32.VK_DECIMAL,
+
+	"f1":  w32.VK_F1,
+	"f2":  w32.VK_F2,
+	"f3":  w32.VK_F3,
+	"f4":  w32.VK_F4,
+	"f5":  w32.VK_F5,
+	"f6":  w32.VK_F6,
+	"f7":  w32.VK_F7,
+	"f8":  w32.VK_F8,
+	"f9":  w32.VK_F9,
+	"f10": w32.VK_F10,
+	"f11": w32.VK_F11,
+	"f12": w32.VK_F12,
+}
+
+func init() {
+	for c := '0'; c <= '9'; c++ {
+		KeyByName[string(c)] = uint16(c)
+	}
+	for c := 'a'; c <= 'z'; c++ {
+		KeyByName[string(c)] = uint16(c - 'a' + 'A')
+	}
+}
+
+// keyNames is the reverse of KeyByName, built lazily so multiple names that
+// map to the same key code (e.g. "enter" and "return") resolve to one
+// canonical name.
+var keyNames map[uint16]string
+
+func buildKeyNames() {
+	keyNames = make(map[uint16]string, len(KeyByName))
+	// Prefer the shorter/more common name when several map to the same code.
+	preferred := []string{
+		"backspace", "tab", "enter", "shift", "ctrl", "alt", "pause",
+		"capslock", "esc", "space", "pageup", "pagedown", "end", "home",
+		"left", "up", "right", "down", "printscreen", "insert", "delete",
+		"win", "rwin", "numlock", "scrolllock",
+	}
+	for _, name := range preferred {
+		if vk, ok := KeyByName[name]; ok {
+			keyNames[vk] = name
+		}
+	}
+	for name, vk := range KeyByName {
+		if _, ok := keyNames[vk]; !ok {
+			keyNames[vk] = name
+		}
+	}
+}
+
+// KeyName returns the human-readable name of a virtual key code, as
+// understood by ParseChord, or "" if the code is not known.
+func KeyName(vk uint16) string {
+	if keyNames == nil {
+		buildKeyNames()
+	}
+	return keyNames[vk]
+}
+
+// ParseChord parses a chord string like "ctrl+shift+f5" into the virtual key
+// codes that make it up, modifiers first in the order given, target key
+// last. Names are matched case-insensitively against KeyByName.
+func ParseChord(chord string) ([]uint16, error) {
+	parts := strings.Split(chord, "+")
+	keys := make([]uint16, 0, len(parts))
+	for _, part := range parts {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		vk, ok := KeyByName[name]
+		if !ok {
+			return nil, errUnknownKey(part)
+		}
+		keys = append(keys, vk)
+	}
+	if len(keys) == 0 {
+		return nil, ErrEmptyChord
+	}
+	return keys, nil
+}
+
+// PressChord parses chord with ParseChord and presses all of its keys down
+// in order, then releases them in reverse order, using a single SendInput
+// call so the whole chord is delivered atomically and cannot be split up by
+// a focus change in between.
+func PressChord(chord string) error {
+	keys, err := ParseChord(chord)
+	if err != nil {
+		return err
+	}
+
+	inputs := make([]w32.INPUT, 0, len(keys)*2)
+	for _, vk := range keys {
+		inputs = append(inputs, w32.KeyboardInput(w32.KEYBDINPUT{Vk: vk}))
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		inputs = append(inputs, w32.KeyboardInput(w32.KEYBDINPUT{
+			Vk:    keys[i],
+			Flags: w32.KEYEVENTF_KEYUP,
+		}))
+	}
+
+	if w32.SendInput(inputs...) == 0 {
+		return ErrBlocked
+	}
+	return nil
+}
+
+// TypeKeys parses each whitespace-separated chord in s with ParseChord and
+// presses them one after another with PressChord, in order. For example
+// TypeKeys("ctrl+a delete") selects everything and deletes it.
+func TypeKeys(s string) error {
+	for _, chord := range strings.Fields(s) {
+		if err := PressChord(chord); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package input
+
+import (
+	"context"
+	"image"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+const (
+	// ErrClipboardEmpty happens when a clipboard reader does not find data
+	// in the format it is looking for.
+	ErrClipboardEmpty errString = "clipboard does not contain the requested format"
+
+	// ErrCreateWindowFailed happens when the winapi function CreateWindowEx
+	// fails.
+	ErrCreateWindowFailed errString = "w32.CreateWindowEx failed"
+
+	// ErrDragQueryFileFailed happens when DragQueryFile does not return the
+	// expected file count for a CF_HDROP clipboard payload.
+	ErrDragQueryFileFailed errString = "w32.DragQueryFile did not return a valid file count"
+
+	// ErrUnsupportedClipboardImageFormat happens when the CF_DIB data on the
+	// clipboard is not an uncompressed 32 bits per pixel bitmap, which is the
+	// only format ClipboardImage currently decodes.
+	ErrUnsupportedClipboardImageFormat errString = "clipboard image is not an uncompressed 32bpp bitmap"
+)
+
+// ClipboardImage returns the image currently on the clipboard, decoded from
+// CF_DIB. It returns ErrClipboardEmpty if the clipboard does not currently
+// hold an image.
+func ClipboardImage() (image.Image, error) {
+	if !openClipboardRetrying() {
+		return nil, ErrOpenClipboardFailed
+	}
+	defer w32.CloseClipboard()
+
+	h := w32.GetClipboardData(w32.CF_DIB)
+	if h == 0 {
+		return nil, ErrClipboardEmpty
+	}
+
+	// CF_DIB clipboard data is an HGLOBAL; it must be locked to get a stable
+	// pointer before it can be read.
+	mem := w32.GlobalLock(w32.HGLOBAL(h))
+	if mem == nil {
+		return nil, ErrGlobalLockFailed
+	}
+	defer w32.GlobalUnlock(w32.HGLOBAL(h))
+
+	info := (*w32.BITMAPINFO)(mem)
+	header := info.BmiHeader
+	// Clipboard DIBs are very commonly 24bpp (e.g. classic Paint, many
+	// browsers), where the row stride is smaller and padded to a 4-byte
+	// boundary rather than a plain 4 bytes per pixel. Rather than guess at
+	// that stride, only decode the 32bpp BI_RGB case this function was
+	// written for.
+	if header.BiBitCount != 32 || header.BiCompression != w32.BI_RGB {
+		return nil, ErrUnsupportedClipboardImageFormat
+	}
+	w := int(header.BiWidth)
+	h2 := int(header.BiHeight)
+	topDown := h2 < 0
+	if topDown {
+		h2 = -h2
+	}
+
+	pixels := unsafe.Pointer(uintptr(mem) + uintptr(header.BiSize))
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h2))
+	stride := w * 4
+	for y := 0; y < h2; y++ {
+		srcY := y
+		if !topDown {
+			srcY = h2 - 1 - y
+		}
+		src := unsafe.Pointer(uintptr(pixels) + uintptr(srcY*stride))
+		row := unsafe.Slice((*byte)(src), stride)
+		for x := 0; x < w; x++ {
+			b := row[x*4+0]
+			g := row[x*4+1]
+			r := row[x*4+2]
+			i := img.PixOffset(x, y)
+			img.Pix[i+0] = r
+			img.Pix[i+1] = g
+			img.Pix[i+2] = b
+			img.Pix[i+3] = 0xff
+		}
+	}
+	return img, nil
+}
+
+// SetClipboardImage puts the given image on the clipboard as CF_DIB.
+func SetClipboardImage(img image.Image) error {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	header := w32.BITMAPINFOHEADER{
+		BiSize:        uint32(unsafe.Sizeof(w32.BITMAPINFOHEADER{})),
+		BiWidth:       int32(w),
+		BiHeight:      int32(h), // positive: bottom-up, as CF_DIB requires
+		BiPlanes:      1,
+		BiBitCount:    32,
+		BiCompression: w32.BI_RGB,
+	}
+	stride := w * 4
+	pixels := make([]byte, stride*h)
+	for y := 0; y < h; y++ {
+		dstY := h - 1 - y // flip to bottom-up
+		for x := 0; x < w; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			i := dstY*stride + x*4
+			pixels[i+0] = byte(b >> 8)
+			pixels[i+1] = byte(g >> 8)
+			pixels[i+2] = byte(r >> 8)
+			pixels[i+3] = 0xff
+		}
+	}
+
+	size := uint32(unsafe.Sizeof(header)) + uint32(len(pixels))
+
+	if !openClipboardRetrying() {
+		return ErrOpenClipboardFailed
+	}
+	defer w32.CloseClipboard()
+
+	if !w32.EmptyClipboard() {
+		return ErrEmptyClipboardFailed
+	}
+
+	mem := w32.GlobalAlloc(w32.GMEM_DDESHARE, size)
+	if mem == 0 {
+		return ErrGlobalAllocFailed
+	}
+	dst := w32.GlobalLock(mem)
+	if dst == nil {
+		w32.GlobalFree(mem)
+		return ErrGlobalLockFailed
+	}
+	w32.MoveMemory(dst, unsafe.Pointer(&header), uint32(unsafe.Sizeof(header)))
+	w32.MoveMemory(
+		unsafe.Pointer(uintptr(dst)+unsafe.Sizeof(header)),
+		unsafe.Pointer(&pixels[0]),
+		uint32(len(pixels)),
+	)
+	w32.GlobalUnlock(mem)
+
+	if w32.SetClipboardData(w32.CF_DIB, w32.HANDLE(unsafe.Pointer(mem))) == 0 {
+		w32.GlobalFree(mem)
+		return ErrSetClipboardDataFailed
+	}
+	return nil
+}
+
+// ClipboardFiles returns the paths of the files currently on the clipboard,
+// decoded from CF_HDROP, e.g. after the user copied files in Explorer. It
+// returns ErrClipboardEmpty if the clipboard does not currently hold files.
+func ClipboardFiles() ([]string, error) {
+	if !openClipboardRetrying() {
+		return nil, ErrOpenClipboardFailed
+	}
+	defer w32.CloseClipboard()
+
+	h := w32.GetClipboardData(w32.CF_HDROP)
+	if h == 0 {
+		return nil, ErrClipboardEmpty
+	}
+	drop := w32.HDROP(unsafe.Pointer(h))
+
+	// Passing file index 0xFFFFFFFF makes DragQueryFile return the file
+	// count instead of a file name, encoded as a decimal string.
+	count, err := strconv.Atoi(w32.DragQueryFile(drop, 0xFFFFFFFF))
+	if err != nil {
+		return nil, ErrDragQueryFileFailed
+	}
+
+	files := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		files = append(files, w32.DragQueryFile(drop, uint(i)))
+	}
+	return files, nil
+}
+
+// ClipboardEvent is sent on the channel returned by WatchClipboard whenever
+// the clipboard contents change.
+type ClipboardEvent struct{}
+
+// WatchClipboard returns a channel that receives a ClipboardEvent every time
+// the clipboard contents change, until ctx is canceled, at which point the
+// channel is closed. The channel holds at most one pending event; changes that
+// happen while an event is still waiting to be received are merged into it.
+// It is implemented with AddClipboardFormatListener and a hidden message-only
+// window running on a dedicated, locked OS thread that pumps messages for as
+// long as the watch is active.
+func WatchClipboard(ctx context.Context) <-chan ClipboardEvent {
+	events := make(chan ClipboardEvent, 1)
+
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		defer close(events)
+
+		className := syscall.StringToUTF16Ptr("gonutz_input_clipboard_watcher")
+		atom := w32.RegisterClassEx(&w32.WNDCLASSEX{
+			WndProc:   syscall.NewCallback(clipboardWatcherWndProc(events)),
+			ClassName: className,
+		})
+		if atom == 0 {
+			return
+		}
+
+		hwnd := w32.CreateWindowEx(
+			0, className, syscall.StringToUTF16Ptr(""), 0,
+			0, 0, 0, 0,
+			w32.HWND_MESSAGE, 0, 0, nil,
+		)
+		if hwnd == 0 {
+			return
+		}
+		defer w32.DestroyWindow(hwnd)
+
+		if !w32.AddClipboardFormatListener(hwnd) {
+			return
+		}
+		defer w32.RemoveClipboardFormatListener(hwnd)
+
+		go func() {
+			<-ctx.Done()
+			w32.PostMessage(hwnd, w32.WM_CLOSE, 0, 0)
+		}()
+
+		var msg w32.MSG
+		for w32.GetMessage(&msg, 0, 0, 0) > 0 {
+			w32.TranslateMessage(&msg)
+			w32.DispatchMessage(&msg)
+		}
+	}()
+
+	return events
+}
+
+// clipboardWatcherWndProc builds a window procedure that forwards every
+// WM_CLIPBOARDUPDATE to events without blocking, dropping it if an event is
+// already pending there, and posts WM_QUIT once the window is told to close.
+func clipboardWatcherWndProc(events chan ClipboardEvent) func(hwnd w32.HWND, msg uint32, wParam w32.WPARAM, lParam w32.LPARAM) w32.LRESULT {
+	return func(hwnd w32.HWND, msg uint32, wParam w32.WPARAM, lParam w32.LPARAM) w32.LRESULT {
+		switch msg {
+		case w32.WM_CLIPBOARDUPDATE:
+			select {
+			case events <- ClipboardEvent{}:
+			default:
+			}
+			return 0
+		case w32.WM_CLOSE:
+			w32.PostQuitMessage(0)
+			return 0
+		default:
+			return w32.LRESULT(w32.DefWindowProc(hwnd, msg, uintptr(wParam), uintptr(lParam)))
+		}
+	}
+}
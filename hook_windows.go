@@ -0,0 +1,179 @@
+package input
+
+import (
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"github.com/gonutz/w32/v2"
+)
+
+const (
+	// ErrSetWindowsHookExFailed happens when the winapi function
+	// SetWindowsHookEx fails.
+	ErrSetWindowsHookExFailed errString = "w32.SetWindowsHookEx failed"
+)
+
+// llkhfInjected is LLKHF_INJECTED and llmhfInjected is LLMHF_INJECTED,
+// neither of which is (yet) exposed by the w32 package. Both mark an event
+// reported to a low-level hook as having been synthesized (e.g. by
+// SendInput) rather than coming from real hardware.
+const (
+	llkhfInjected = 0x00000010
+	llmhfInjected = 0x00000001
+)
+
+// KeyEvent is reported by a keyboard hook installed with
+// InstallKeyboardHook.
+type KeyEvent struct {
+	// VKCode is the virtual key code of the key that was pressed or
+	// released, e.g. w32.VK_RETURN.
+	VKCode uint32
+	// ScanCode is the hardware scan code of the key.
+	ScanCode uint32
+	// KeyUp is true if the key was released, false if it was pressed down.
+	KeyUp bool
+	// Injected is true if the event was synthesized by SendInput (by this
+	// process or another one) rather than coming from real hardware.
+	Injected bool
+}
+
+// MouseEvent is reported by a mouse hook installed with InstallMouseHook.
+type MouseEvent struct {
+	// X and Y are the screen coordinates of the cursor when the event
+	// happened.
+	X, Y int
+	// Message is the raw window message that triggered the event, e.g.
+	// w32.WM_LBUTTONDOWN or w32.WM_MOUSEMOVE.
+	Message uint32
+	// MouseData carries the WHEEL_DELTA multiple for wheel messages, or the
+	// XBUTTON identifier for X button messages.
+	MouseData int32
+	// Injected is true if the event was synthesized by SendInput rather
+	// than coming from real hardware.
+	Injected bool
+}
+
+// Uninstall removes a hook installed with InstallKeyboardHook or
+// InstallMouseHook. It is safe to call more than once.
+type Uninstall func()
+
+// InstallKeyboardHook installs a global low-level keyboard hook and calls fn
+// for every key event system-wide, including events from other processes. If
+// fn returns true, the event is swallowed and never reaches its target
+// window. fn is called on a dedicated OS thread, not the goroutine that
+// called InstallKeyboardHook, so it must not touch state that is only safe to
+// use from one particular goroutine. Call the returned Uninstall function to
+// remove the hook and stop the background thread.
+func InstallKeyboardHook(fn func(event KeyEvent) (swallow bool)) (Uninstall, error) {
+	return installHook(w32.WH_KEYBOARD_LL, func(nCode int, wParam w32.WPARAM, lParam w32.LPARAM) w32.LRESULT {
+		if nCode >= 0 {
+			kb := (*w32.KBDLLHOOKSTRUCT)(unsafe.Pointer(uintptr(lParam)))
+			swallow := fn(KeyEvent{
+				VKCode:   uint32(kb.VkCode),
+				ScanCode: uint32(kb.ScanCode),
+				KeyUp:    uintptr(wParam) == w32.WM_KEYUP || uintptr(wParam) == w32.WM_SYSKEYUP,
+				Injected: kb.Flags&llkhfInjected != 0,
+			})
+			if swallow {
+				return 1
+			}
+		}
+		return w32.CallNextHookEx(0, nCode, wParam, lParam)
+	})
+}
+
+// InstallMouseHook installs a global low-level mouse hook and calls fn for
+// every mouse event system-wide, including events from other processes. If
+// fn returns true, the event is swallowed and never reaches its target
+// window. fn is called on a dedicated OS thread, not the goroutine that
+// called InstallMouseHook, so it must not touch state that is only safe to
+// use from one particular goroutine. Call the returned Uninstall function to
+// remove the hook and stop the background thread.
+func InstallMouseHook(fn func(event MouseEvent) (swallow bool)) (Uninstall, error) {
+	return installHook(w32.WH_MOUSE_LL, func(nCode int, wParam w32.WPARAM, lParam w32.LPARAM) w32.LRESULT {
+		if nCode >= 0 {
+			ms := (*w32.MSLLHOOKSTRUCT)(unsafe.Pointer(uintptr(lParam)))
+			swallow := fn(MouseEvent{
+				X:         int(ms.Pt.X),
+				Y:         int(ms.Pt.Y),
+				Message:   uint32(wParam),
+				MouseData: int32(ms.MouseData) >> 16,
+				Injected:  ms.Flags&llmhfInjected != 0,
+			})
+			if swallow {
+				return 1
+			}
+		}
+		return w32.CallNextHookEx(0, nCode, wParam, lParam)
+	})
+}
+
+// installHook runs proc as a Windows hook procedure of type idHook on a
+// dedicated, locked OS thread. That thread owns the hook and pumps messages
+// for as long as the hook is installed, which is what SetWindowsHookEx
+// requires for WH_KEYBOARD_LL and WH_MOUSE_LL hooks to fire reliably.
+func installHook(idHook int, proc w32.HOOKPROC) (Uninstall, error) {
+	type setupResult struct {
+		threadID uint32
+		err      error
+	}
+	setup := make(chan setupResult, 1)
+
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+
+		hook := w32.SetWindowsHookEx(idHook, proc, 0, 0)
+		if hook == 0 {
+			setup <- setupResult{err: ErrSetWindowsHookExFailed}
+			return
+		}
+		defer w32.UnhookWindowsHookEx(hook)
+
+		setup <- setupResult{threadID: getCurrentThreadID()}
+
+		// Pump messages so the hook procedure gets called by the system.
+		// This loop only returns once WM_QUIT is posted to this thread by
+		// Uninstall.
+		var msg w32.MSG
+		for w32.GetMessage(&msg, 0, 0, 0) > 0 {
+			w32.TranslateMessage(&msg)
+			w32.DispatchMessage(&msg)
+		}
+	}()
+
+	result := <-setup
+	if result.err != nil {
+		return func() {}, result.err
+	}
+
+	uninstalled := false
+	return func() {
+		if uninstalled {
+			return
+		}
+		uninstalled = true
+		postThreadQuitMessage(result.threadID)
+	}, nil
+}
+
+// The w32 package does not expose GetCurrentThreadId or PostThreadMessage,
+// both of which are needed to stop the message loop a hook runs on, so we
+// call them directly.
+var (
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procGetCurrentThreadID = kernel32.NewProc("GetCurrentThreadId")
+
+	user32                 = syscall.NewLazyDLL("user32.dll")
+	procPostThreadMessageW = user32.NewProc("PostThreadMessageW")
+)
+
+func getCurrentThreadID() uint32 {
+	r, _, _ := procGetCurrentThreadID.Call()
+	return uint32(r)
+}
+
+func postThreadQuitMessage(threadID uint32) {
+	procPostThreadMessageW.Call(uintptr(threadID), uintptr(w32.WM_QUIT), 0, 0)
+}
@@ -26,8 +26,48 @@ const (
 
 	// ErrSetCursorFailed happens when the winapi function SetCursorPos fails.
 	ErrSetCursorFailed errString = "w32.SetCursorPos failed"
+
+	// ErrOpenClipboardFailed happens when the winapi function OpenClipboard
+	// keeps failing, even after retrying for a short while.
+	ErrOpenClipboardFailed errString = "w32.OpenClipboard failed"
+
+	// ErrEmptyClipboardFailed happens when the winapi function EmptyClipboard
+	// fails.
+	ErrEmptyClipboardFailed errString = "w32.EmptyClipboard failed"
+
+	// ErrGlobalAllocFailed happens when the winapi function GlobalAlloc
+	// fails.
+	ErrGlobalAllocFailed errString = "w32.GlobalAlloc failed"
+
+	// ErrGlobalLockFailed happens when the winapi function GlobalLock fails.
+	ErrGlobalLockFailed errString = "w32.GlobalLock failed"
+
+	// ErrSetClipboardDataFailed happens when the winapi function
+	// SetClipboardData fails.
+	ErrSetClipboardDataFailed errString = "w32.SetClipboardData failed"
+)
+
+// clipboardOpenRetries and clipboardOpenRetryDelay control how hard
+// openClipboardRetrying tries before giving up, since another process
+// (commonly a clipboard manager) can hold the clipboard open for a brief
+// moment.
+const (
+	clipboardOpenRetries    = 5
+	clipboardOpenRetryDelay = 5 * time.Millisecond
 )
 
+// openClipboardRetrying calls w32.OpenClipboard, retrying a few times with a
+// short delay if the clipboard is currently held open by another process.
+func openClipboardRetrying() bool {
+	for i := 0; i < clipboardOpenRetries; i++ {
+		if w32.OpenClipboard(0) {
+			return true
+		}
+		time.Sleep(clipboardOpenRetryDelay)
+	}
+	return false
+}
+
 func clickAt(x, y int, down, up uint32) error {
 	if !w32.SetCursorPos(x, y) {
 		return ErrSetCursorFailed
@@ -185,15 +225,25 @@ func LeftDoubleClick() error {
 	return nil
 }
 
-// Type will write the given text using Alt+Numpad numbers. It will sleep the
-// smallest, non-0 delay between two letters.
-func Type(s string) error {
-	return TypeWithDelay(s, 1)
+// TypeAltNumpad will write the given text using Alt+Numpad numbers. It will
+// sleep the smallest, non-0 delay between two letters.
+//
+// This is the original implementation of Type, kept around because some
+// applications do not accept Unicode input but still understand Alt+Numpad
+// sequences. Prefer Type/TypeUnicode, which work more reliably and support
+// the full Unicode range.
+func TypeAltNumpad(s string) error {
+	return TypeAltNumpadWithDelay(s, 1)
 }
 
-// TypeWithDelay will write the given text using Alt+Numpad numbers. It will
-// sleep the given delay between two letters.
-func TypeWithDelay(s string, delay time.Duration) error {
+// TypeAltNumpadWithDelay will write the given text using Alt+Numpad numbers.
+// It will sleep the given delay between two letters.
+//
+// This is the original implementation of TypeWithDelay, kept around because
+// some applications do not accept Unicode input but still understand
+// Alt+Numpad sequences. Prefer TypeWithDelay/TypeUnicodeWithDelay, which work
+// more reliably and support the full Unicode range.
+func TypeAltNumpadWithDelay(s string, delay time.Duration) error {
 	toScanCode := func(vk uint) uint16 {
 		return uint16(w32.MapVirtualKey(vk, w32.MAPVK_VK_TO_VSC))
 	}
@@ -343,21 +393,37 @@ func ClipboardText() string {
 }
 
 // SetClipboardText sets the contents of the clipboard to the given string.
-func SetClipboardText(text string) {
-	if w32.OpenClipboard(0) {
-		w32.EmptyClipboard()
-		data := syscall.StringToUTF16(text)
-		clipBuffer := w32.GlobalAlloc(w32.GMEM_DDESHARE, uint32(len(data)*2))
-		w32.MoveMemory(
-			w32.GlobalLock(clipBuffer),
-			unsafe.Pointer(&data[0]),
-			uint32(len(data)*2),
-		)
-		w32.GlobalUnlock(clipBuffer)
-		w32.SetClipboardData(
-			w32.CF_UNICODETEXT,
-			w32.HANDLE(unsafe.Pointer(clipBuffer)),
-		)
-		w32.CloseClipboard()
+// The clipboard is sometimes briefly held open by another process, so this
+// retries OpenClipboard a few times before giving up.
+func SetClipboardText(text string) error {
+	if !openClipboardRetrying() {
+		return ErrOpenClipboardFailed
+	}
+	defer w32.CloseClipboard()
+
+	if !w32.EmptyClipboard() {
+		return ErrEmptyClipboardFailed
 	}
+
+	data := syscall.StringToUTF16(text)
+	size := uint32(len(data) * 2)
+	clipBuffer := w32.GlobalAlloc(w32.GMEM_DDESHARE, size)
+	if clipBuffer == 0 {
+		return ErrGlobalAllocFailed
+	}
+
+	dst := w32.GlobalLock(clipBuffer)
+	if dst == nil {
+		w32.GlobalFree(clipBuffer)
+		return ErrGlobalLockFailed
+	}
+	w32.MoveMemory(dst, unsafe.Pointer(&data[0]), size)
+	w32.GlobalUnlock(clipBuffer)
+
+	if w32.SetClipboardData(w32.CF_UNICODETEXT, w32.HANDLE(unsafe.Pointer(clipBuffer))) == 0 {
+		w32.GlobalFree(clipBuffer)
+		return ErrSetClipboardDataFailed
+	}
+
+	return nil
 }
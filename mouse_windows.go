@@ -0,0 +1,102 @@
+package input
+
+import (
+	"math"
+	"time"
+
+	"github.com/gonutz/w32/v2"
+)
+
+// wheelDelta is WHEEL_DELTA, one notch of a standard mouse wheel.
+const wheelDelta = 120
+
+// ScrollWheel scrolls the mouse wheel vertically. Positive dz scrolls away
+// from the user (usually up/forward), negative dz scrolls towards the user
+// (usually down/backward). dz is given in notches, so ScrollWheel(1) is one
+// click of a typical wheel.
+func ScrollWheel(dz int) error {
+	n := w32.SendInput(
+		w32.MouseInput(w32.MOUSEINPUT{
+			Flags: w32.MOUSEEVENTF_WHEEL,
+			// MouseData is a uint32, so negative notches are carried as their
+			// two's-complement int32 bit pattern.
+			MouseData: uint32(int32(dz * wheelDelta)),
+		}),
+	)
+	if n == 0 {
+		return ErrBlocked
+	}
+	return nil
+}
+
+// ScrollHorizontal scrolls the mouse wheel horizontally, tilting it to the
+// right for positive dx and to the left for negative dx. dx is given in
+// notches, so ScrollHorizontal(1) is one click of a typical tilt wheel.
+func ScrollHorizontal(dx int) error {
+	n := w32.SendInput(
+		w32.MouseInput(w32.MOUSEINPUT{
+			Flags:     w32.MOUSEEVENTF_HWHEEL,
+			MouseData: uint32(int32(dx * wheelDelta)),
+		}),
+	)
+	if n == 0 {
+		return ErrBlocked
+	}
+	return nil
+}
+
+// MousePosition returns the current position of the mouse cursor in screen
+// coordinates.
+func MousePosition() (x, y int, err error) {
+	x, y, ok := w32.GetCursorPos()
+	if !ok {
+		return 0, 0, ErrGetCursorFailed
+	}
+	return x, y, nil
+}
+
+// MoveMouseSmooth moves the mouse cursor from its current position to x,y
+// over the given duration, following a cubic ease-in-out curve and updating
+// the cursor position at roughly 120Hz. This looks like a human moving the
+// mouse instead of it jumping straight to the target, which some programs
+// use as a signal to tell automated input apart from real users.
+func MoveMouseSmooth(x, y int, duration time.Duration) error {
+	startX, startY, err := MousePosition()
+	if err != nil {
+		return err
+	}
+	if duration <= 0 {
+		return MoveMouseTo(x, y)
+	}
+
+	const frameTime = time.Second / 120
+	steps := int(duration / frameTime)
+	if steps < 1 {
+		steps = 1
+	}
+
+	for i := 1; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		e := easeInOutCubic(t)
+		curX := startX + int(math.Round(float64(x-startX)*e))
+		curY := startY + int(math.Round(float64(y-startY)*e))
+		if !w32.SetCursorPos(curX, curY) {
+			return ErrSetCursorFailed
+		}
+		if i < steps {
+			time.Sleep(frameTime)
+		}
+	}
+
+	return MoveMouseTo(x, y)
+}
+
+// easeInOutCubic maps t in [0,1] to an eased progress in [0,1], starting and
+// ending slowly with acceleration in the middle.
+func easeInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	f := -2*t + 2
+	return 1 - f*f*f/2
+}
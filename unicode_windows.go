@@ -0,0 +1,85 @@
+package input
+
+import (
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/gonutz/w32/v2"
+)
+
+// Type will write the given text using KEYEVENTF_UNICODE, so it works in
+// applications and locales where TypeAltNumpad does not, and can emit any
+// Unicode character, not just the ones below U+0100. It will sleep the
+// smallest, non-0 delay between two letters.
+func Type(s string) error {
+	return TypeUnicodeWithDelay(s, 1)
+}
+
+// TypeWithDelay will write the given text using KEYEVENTF_UNICODE. It will
+// sleep the given delay between two letters.
+func TypeWithDelay(s string, delay time.Duration) error {
+	return TypeUnicodeWithDelay(s, delay)
+}
+
+// TypeUnicode will write the given text using KEYEVENTF_UNICODE. It will
+// sleep the smallest, non-0 delay between two letters.
+func TypeUnicode(s string) error {
+	return TypeUnicodeWithDelay(s, 1)
+}
+
+// TypeUnicodeWithDelay will write the given text using KEYEVENTF_UNICODE,
+// sleeping the given delay between two letters. '\r' and '\n' are sent as
+// VK_RETURN and '\b' as VK_BACK, via PressKey, everything else is sent as its
+// UTF-16 code unit(s) with the KEYEVENTF_UNICODE flag; runes outside the
+// Basic Multilingual Plane are sent as a surrogate pair, both halves going
+// down before either one comes back up.
+func TypeUnicodeWithDelay(s string, delay time.Duration) error {
+	// Unify line breaks to '\r' which is the virtual key code for VK_RETURN.
+	s = strings.Replace(s, "\r\n", "\r", -1)
+	s = strings.Replace(s, "\n", "\r", -1)
+
+	for _, r := range s {
+		switch r {
+		case '\r':
+			if err := PressKey(w32.VK_RETURN); err != nil {
+				return err
+			}
+		case '\b':
+			if err := PressKey(w32.VK_BACK); err != nil {
+				return err
+			}
+		default:
+			if err := typeUnicodeRune(r); err != nil {
+				return err
+			}
+		}
+		time.Sleep(delay)
+	}
+	return nil
+}
+
+// typeUnicodeRune sends one rune as one or two KEYBDINPUT down events
+// followed by the matching up events, using KEYEVENTF_UNICODE.
+func typeUnicodeRune(r rune) error {
+	units := utf16.Encode([]rune{r})
+
+	inputs := make([]w32.INPUT, 0, len(units)*2)
+	for _, unit := range units {
+		inputs = append(inputs, w32.KeyboardInput(w32.KEYBDINPUT{
+			Scan:  unit,
+			Flags: w32.KEYEVENTF_UNICODE,
+		}))
+	}
+	for _, unit := range units {
+		inputs = append(inputs, w32.KeyboardInput(w32.KEYBDINPUT{
+			Scan:  unit,
+			Flags: w32.KEYEVENTF_UNICODE | w32.KEYEVENTF_KEYUP,
+		}))
+	}
+
+	if w32.SendInput(inputs...) == 0 {
+		return ErrBlocked
+	}
+	return nil
+}